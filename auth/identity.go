@@ -0,0 +1,118 @@
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Permission is a single action an identity may be granted against the proxy.
+type Permission string
+
+const (
+	PermissionRead        Permission = "read"
+	PermissionWrite       Permission = "write"
+	PermissionPutOnly     Permission = "put-only"
+	PermissionAdmin       Permission = "admin"
+	PermissionImpersonate Permission = "impersonate"
+)
+
+// Identity is a single access-key/secret-key credential known to the proxy, along with
+// the set of actions and commitment namespaces (key prefixes) it is permitted to touch.
+type Identity struct {
+	AccessKeyID     string       `json:"access_key_id" yaml:"access_key_id"`
+	SecretAccessKey string       `json:"secret_access_key" yaml:"secret_access_key"`
+	Permissions     []Permission `json:"permissions" yaml:"permissions"`
+	Namespaces      []string     `json:"namespaces" yaml:"namespaces"`
+}
+
+// Allows reports whether the identity has been granted the given permission.
+// PermissionPutOnly implies PermissionWrite: an identity scoped to put-only is meant to be
+// able to write blobs (just not read them back or hold broader write-adjacent access), so
+// without this, put-only would be configuration that can never actually PUT anything.
+func (id Identity) Allows(p Permission) bool {
+	for _, perm := range id.Permissions {
+		if perm == p || perm == PermissionAdmin {
+			return true
+		}
+		if p == PermissionWrite && perm == PermissionPutOnly {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsNamespace reports whether key falls under one of the identity's allowed namespaces.
+// An identity with no configured namespaces is unrestricted. An empty key always passes:
+// it means the request has no commitment yet to restrict on (e.g. a keyless PUT, whose
+// commitment is only known after the blob is written), not that the identity is unrestricted.
+func (id Identity) AllowsNamespace(key string) bool {
+	if len(id.Namespaces) == 0 || key == "" {
+		return true
+	}
+	for _, ns := range id.Namespaces {
+		if strings.HasPrefix(key, ns) {
+			return true
+		}
+	}
+	return false
+}
+
+// IdentityStore holds the set of known identities, keyed by access key ID, and can be
+// hot-reloaded from disk (e.g. in response to SIGHUP) without interrupting in-flight requests.
+type IdentityStore struct {
+	mu         sync.RWMutex
+	path       string
+	identities map[string]Identity
+}
+
+// NewIdentityStore loads identities from the JSON or YAML file at path. The format is
+// inferred from the file extension (.json, .yaml, .yml).
+func NewIdentityStore(path string) (*IdentityStore, error) {
+	s := &IdentityStore{path: path}
+	if err := s.Reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Reload re-reads the identity file from disk, atomically swapping the in-memory set on success.
+// A malformed file leaves the previously loaded identities intact.
+func (s *IdentityStore) Reload() error {
+	raw, err := os.ReadFile(s.path)
+	if err != nil {
+		return fmt.Errorf("failed to read identity file: %w", err)
+	}
+
+	var list []Identity
+	if strings.HasSuffix(s.path, ".yaml") || strings.HasSuffix(s.path, ".yml") {
+		err = yaml.Unmarshal(raw, &list)
+	} else {
+		err = json.Unmarshal(raw, &list)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to parse identity file: %w", err)
+	}
+
+	identities := make(map[string]Identity, len(list))
+	for _, id := range list {
+		identities[id.AccessKeyID] = id
+	}
+
+	s.mu.Lock()
+	s.identities = identities
+	s.mu.Unlock()
+	return nil
+}
+
+// Get returns the identity for the given access key ID, if known.
+func (s *IdentityStore) Get(accessKeyID string) (Identity, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	id, ok := s.identities[accessKeyID]
+	return id, ok
+}
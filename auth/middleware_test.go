@@ -0,0 +1,81 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// recordedAuthRequest captures a single RecordAuthRequest call, for asserting the exact
+// calls a fake metricer observed.
+type recordedAuthRequest struct {
+	accessKeyID string
+	allowed     bool
+}
+
+type fakeMetricer struct {
+	authRequests   []recordedAuthRequest
+	impersonations [][2]string
+}
+
+func (f *fakeMetricer) RecordRPCServerRequest(string) func(status, mode, version string) {
+	return func(string, string, string) {}
+}
+func (f *fakeMetricer) RecordBlobSize(string, string, string, int) {}
+func (f *fakeMetricer) RecordAuthRequest(accessKeyID string, allowed bool) {
+	f.authRequests = append(f.authRequests, recordedAuthRequest{accessKeyID, allowed})
+}
+func (f *fakeMetricer) RecordImpersonation(real, effective string) {
+	f.impersonations = append(f.impersonations, [2]string{real, effective})
+}
+
+func TestMiddleware_RecordsAuthDecisions(t *testing.T) {
+	store := &IdentityStore{identities: map[string]Identity{
+		testAccessKeyID: {AccessKeyID: testAccessKeyID, SecretAccessKey: testSecretKey, Permissions: []Permission{PermissionRead}},
+	}}
+	metricer := &fakeMetricer{}
+	m := (&Middleware{log: log.New(), store: store}).WithMetrics(metricer)
+
+	now := time.Now().UTC()
+
+	if _, err := m.Authenticate(signedRequest(t, testAccessKeyID, testSecretKey, now), PermissionRead, ""); err != nil {
+		t.Fatalf("expected read permission to be granted: %v", err)
+	}
+	if len(metricer.authRequests) != 1 || !metricer.authRequests[0].allowed {
+		t.Fatalf("expected one allowed auth request to be recorded, got %+v", metricer.authRequests)
+	}
+
+	if _, err := m.Authenticate(signedRequest(t, testAccessKeyID, testSecretKey, now), PermissionWrite, ""); err != ErrForbidden {
+		t.Fatalf("expected write permission to be denied, got: %v", err)
+	}
+	if len(metricer.authRequests) != 2 || metricer.authRequests[1].allowed {
+		t.Fatalf("expected a second, denied auth request to be recorded, got %+v", metricer.authRequests)
+	}
+}
+
+func TestMiddleware_RecordsImpersonation(t *testing.T) {
+	const targetAccessKeyID = "AKIDTARGET"
+	store := &IdentityStore{identities: map[string]Identity{
+		testAccessKeyID:   {AccessKeyID: testAccessKeyID, SecretAccessKey: testSecretKey, Permissions: []Permission{PermissionImpersonate}},
+		targetAccessKeyID: {AccessKeyID: targetAccessKeyID, Permissions: []Permission{PermissionRead}},
+	}}
+	metricer := &fakeMetricer{}
+	m := (&Middleware{log: log.New(), store: store}).WithMetrics(metricer)
+
+	now := time.Now().UTC()
+	req := signedRequest(t, testAccessKeyID, testSecretKey, now)
+	req.Header.Set(ImpersonateHeader, targetAccessKeyID)
+
+	result, err := m.Authenticate(req, PermissionRead, "")
+	if err != nil {
+		t.Fatalf("expected impersonated read to be granted: %v", err)
+	}
+	if result.Real.AccessKeyID != testAccessKeyID || result.Effective.AccessKeyID != targetAccessKeyID {
+		t.Fatalf("expected real=%s effective=%s, got real=%s effective=%s",
+			testAccessKeyID, targetAccessKeyID, result.Real.AccessKeyID, result.Effective.AccessKeyID)
+	}
+	if len(metricer.impersonations) != 1 || metricer.impersonations[0] != [2]string{testAccessKeyID, targetAccessKeyID} {
+		t.Fatalf("expected one impersonation to be recorded, got %+v", metricer.impersonations)
+	}
+}
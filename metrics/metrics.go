@@ -0,0 +1,83 @@
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Namespace is the prefix applied to every metric this package registers.
+const Namespace = "eigenda_proxy"
+
+// Metricer is the set of metrics the server and auth layers record against each request.
+// It is implemented by *Metrics. The auth middleware may be left without one (e.g. in
+// tests), since it nil-checks before recording; the server's WithMetrics middleware calls
+// it unconditionally, so a Server needs a non-nil Metricer.
+type Metricer interface {
+	// RecordRPCServerRequest starts timing a request for method, returning a function that
+	// records its duration once the response status, commitment mode, and cert version are
+	// known.
+	RecordRPCServerRequest(method string) func(status, mode, version string)
+	// RecordBlobSize records the size in bytes of a blob read or written via method.
+	RecordBlobSize(method, mode, version string, size int)
+	// RecordAuthRequest records an authentication/authorization decision for accessKeyID.
+	RecordAuthRequest(accessKeyID string, allowed bool)
+	// RecordImpersonation records a request evaluated under an impersonated identity.
+	RecordImpersonation(real, effective string)
+}
+
+// Metrics is the prometheus-backed Metricer used in production.
+type Metrics struct {
+	rpcServerRequestDuration *prometheus.HistogramVec
+	blobSizeBytes            *prometheus.HistogramVec
+	authRequestsTotal        *prometheus.CounterVec
+	impersonationsTotal      *prometheus.CounterVec
+}
+
+// NewMetrics registers and returns the proxy's metrics against registry.
+func NewMetrics(registry *prometheus.Registry) *Metrics {
+	m := &Metrics{
+		rpcServerRequestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "rpc_server_request_duration_seconds",
+			Help:      "Duration of RPC server requests, labeled by method, status, commitment mode, and cert version.",
+		}, []string{"method", "status", "mode", "version"}),
+		blobSizeBytes: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "blob_size_bytes",
+			Help:      "Size in bytes of blobs read or written, labeled by method, commitment mode, and cert version.",
+		}, []string{"method", "mode", "version"}),
+		authRequestsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "auth_requests_total",
+			Help:      "Count of authentication/authorization decisions, labeled by access key ID and whether the request was allowed.",
+		}, []string{"access_key_id", "allowed"}),
+		impersonationsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "impersonations_total",
+			Help:      "Count of requests evaluated under an impersonated identity, labeled by the real and effective access key IDs.",
+		}, []string{"real", "effective"}),
+	}
+	registry.MustRegister(m.rpcServerRequestDuration, m.blobSizeBytes, m.authRequestsTotal, m.impersonationsTotal)
+	return m
+}
+
+func (m *Metrics) RecordRPCServerRequest(method string) func(status, mode, version string) {
+	start := time.Now()
+	return func(status, mode, version string) {
+		m.rpcServerRequestDuration.WithLabelValues(method, status, mode, version).Observe(time.Since(start).Seconds())
+	}
+}
+
+func (m *Metrics) RecordBlobSize(method, mode, version string, size int) {
+	m.blobSizeBytes.WithLabelValues(method, mode, version).Observe(float64(size))
+}
+
+func (m *Metrics) RecordAuthRequest(accessKeyID string, allowed bool) {
+	m.authRequestsTotal.WithLabelValues(accessKeyID, strconv.FormatBool(allowed)).Inc()
+}
+
+func (m *Metrics) RecordImpersonation(real, effective string) {
+	m.impersonationsTotal.WithLabelValues(real, effective).Inc()
+}
@@ -0,0 +1,347 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Layr-Labs/eigenda-proxy/commitments"
+	"github.com/Layr-Labs/eigenda-proxy/store"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/google/uuid"
+)
+
+// UploadsRoute is modeled on the Docker registry blob upload flow, letting clients stream
+// blobs that approach the write timeout in HandlePut without holding the full body in
+// memory between requests, and resume an upload after a network failure. The complete
+// blob is assembled in memory only when the upload is finalized.
+const UploadsRoute = "/put/uploads/"
+
+// DefaultUploadTTL bounds how long an incomplete upload is retained before it is eligible
+// for cleanup.
+const DefaultUploadTTL = 24 * time.Hour
+
+// chunkedUpload tracks a single in-progress resumable upload. Each PATCH persists its chunk
+// to the backing store under its own key immediately, so the server process holds only the
+// offset and the list of chunk start offsets, never the accumulated body, between requests.
+// chunkOffsets records the start offset of every persisted chunk in the order they were
+// written, so handleFinalize can read them back and reassemble the blob in order.
+// appendMu serializes the whole check-persist-append sequence in handleAppend, so that
+// two concurrent PATCH requests for the same upload (e.g. a client retry) can't both pass
+// the offset check and clobber each other's chunk.
+type chunkedUpload struct {
+	id           string
+	offset       int64
+	chunkOffsets []int64
+	createdAt    time.Time
+	appendMu     sync.Mutex
+}
+
+// uploadBackend is the subset of PrecomputedKeyStore the chunked upload manager needs to
+// persist partial uploads; S3Store and any local-disk equivalent satisfy it.
+type uploadBackend interface {
+	Get(ctx context.Context, key []byte) ([]byte, error)
+	Put(ctx context.Context, key []byte, value []byte) error
+	Delete(ctx context.Context, key []byte) error
+}
+
+// ChunkedUploadManager implements the resumable/chunked PUT protocol: POST creates an
+// upload, PATCH appends a byte range, GET reports the committed offset, and PUT finalizes
+// the upload by submitting the assembled blob to EigenDA.
+type ChunkedUploadManager struct {
+	log     log.Logger
+	router  store.IRouter
+	backend uploadBackend
+	ttl     time.Duration
+
+	mu      sync.Mutex
+	uploads map[string]*chunkedUpload
+}
+
+// NewChunkedUploadManager constructs a manager that persists partial uploads to backend,
+// keyed by upload UUID, and finalizes completed uploads through router.
+func NewChunkedUploadManager(router store.IRouter, backend uploadBackend, ttl time.Duration, log log.Logger) *ChunkedUploadManager {
+	if ttl <= 0 {
+		ttl = DefaultUploadTTL
+	}
+	return &ChunkedUploadManager{
+		log:     log,
+		router:  router,
+		backend: backend,
+		ttl:     ttl,
+		uploads: make(map[string]*chunkedUpload),
+	}
+}
+
+func (m *ChunkedUploadManager) Handle(w http.ResponseWriter, r *http.Request) {
+	m.purgeExpired()
+
+	id := strings.TrimPrefix(r.URL.Path, UploadsRoute)
+	switch {
+	case r.Method == http.MethodPost && id == "":
+		m.handleCreate(w, r)
+	case r.Method == http.MethodGet && id == "":
+		m.handleList(w)
+	case r.Method == http.MethodPatch:
+		m.handleAppend(w, r, id)
+	case r.Method == http.MethodGet:
+		m.handleStatus(w, id)
+	case r.Method == http.MethodPut:
+		m.handleFinalize(w, r, id)
+	case r.Method == http.MethodDelete:
+		m.handleCancel(w, r, id)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (m *ChunkedUploadManager) handleCreate(w http.ResponseWriter, r *http.Request) {
+	id := uuid.New().String()
+
+	m.mu.Lock()
+	m.uploads[id] = &chunkedUpload{id: id, createdAt: time.Now()}
+	m.mu.Unlock()
+
+	location := UploadsRoute + id
+	w.Header().Set("Location", location)
+	w.Header().Set("Docker-Upload-UUID", id)
+	w.Header().Set("Range", "0-0")
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// handleAppend persists the request body as its own chunk, keyed by the upload id and its
+// start offset, rejecting ranges that don't start where the upload currently leaves off
+// with 416, per the out-of-order rejection the resumable protocol requires. Each chunk is
+// written once and never read back until handleFinalize assembles the complete upload, so
+// a single PATCH moves O(chunk size) data rather than re-persisting everything received so
+// far.
+func (m *ChunkedUploadManager) handleAppend(w http.ResponseWriter, r *http.Request, id string) {
+	m.mu.Lock()
+	upload, ok := m.uploads[id]
+	m.mu.Unlock()
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	start, _, err := parseContentRange(r.Header.Get("Content-Range"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	chunk, err := io.ReadAll(r.Body)
+	if err != nil {
+		m.log.Error("chunked upload: failed to read chunk body", "id", id, "err", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	// hold the upload's own lock across the whole check-write sequence, so a concurrent
+	// retry of the same PATCH can't also pass the offset check and write a conflicting
+	// chunk at the same offset.
+	upload.appendMu.Lock()
+	defer upload.appendMu.Unlock()
+
+	if start != upload.offset {
+		w.Header().Set("Range", fmt.Sprintf("0-%d", upload.offset))
+		w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+		return
+	}
+
+	if err := m.backend.Put(r.Context(), chunkKey(id, start), chunk); err != nil {
+		m.log.Error("chunked upload: failed to persist chunk", "id", id, "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	upload.chunkOffsets = append(upload.chunkOffsets, start)
+	upload.offset += int64(len(chunk))
+
+	w.Header().Set("Range", fmt.Sprintf("0-%d", upload.offset))
+	w.Header().Set("Docker-Upload-UUID", id)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (m *ChunkedUploadManager) handleStatus(w http.ResponseWriter, id string) {
+	m.mu.Lock()
+	upload, ok := m.uploads[id]
+	m.mu.Unlock()
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	upload.appendMu.Lock()
+	offset := upload.offset
+	upload.appendMu.Unlock()
+
+	w.Header().Set("Range", fmt.Sprintf("0-%d", offset))
+	w.Header().Set("Docker-Upload-UUID", id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleFinalize submits the assembled upload to EigenDA and returns the resulting
+// commitment. If a digest query parameter is supplied, it is checked against
+// keccak256(blob) before submission.
+func (m *ChunkedUploadManager) handleFinalize(w http.ResponseWriter, r *http.Request, id string) {
+	m.mu.Lock()
+	upload, ok := m.uploads[id]
+	m.mu.Unlock()
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	upload.appendMu.Lock()
+	chunkOffsets := append([]int64(nil), upload.chunkOffsets...)
+	upload.appendMu.Unlock()
+
+	blob, err := assembleChunks(r.Context(), m.backend, id, chunkOffsets)
+	if err != nil {
+		m.log.Error("chunked upload: failed to assemble upload", "id", id, "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	if digest := r.URL.Query().Get("digest"); digest != "" {
+		if !strings.EqualFold(digest, crypto.Keccak256Hash(blob).Hex()) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+	}
+
+	commitment, err := m.router.Put(r.Context(), commitments.SimpleCommitmentMode, nil, blob)
+	if err != nil {
+		m.log.Error("chunked upload: failed to submit assembled blob", "id", id, "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	m.deleteChunks(r.Context(), id, chunkOffsets)
+	m.mu.Lock()
+	delete(m.uploads, id)
+	m.mu.Unlock()
+
+	m.log.Info(fmt.Sprintf("chunked upload: finalized upload %s, commitment %x\n", id, commitment), identityLogFields(r.Context())...)
+	w.Header().Set("x-amz-meta-commitment", hexutil.Encode(commitment))
+	w.WriteHeader(http.StatusCreated)
+}
+
+// handleCancel is the admin-facing cancelation endpoint: it discards an in-progress
+// upload's persisted chunks and forgets its offset.
+func (m *ChunkedUploadManager) handleCancel(w http.ResponseWriter, r *http.Request, id string) {
+	m.mu.Lock()
+	upload, ok := m.uploads[id]
+	if ok {
+		delete(m.uploads, id)
+	}
+	m.mu.Unlock()
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	upload.appendMu.Lock()
+	chunkOffsets := append([]int64(nil), upload.chunkOffsets...)
+	upload.appendMu.Unlock()
+
+	m.deleteChunks(r.Context(), id, chunkOffsets)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// assembleChunks reads back every chunk persisted for id, in the order recorded by
+// chunkOffsets, and concatenates them into the complete upload. This is the only point in
+// the resumable-upload flow that holds the whole blob in memory at once, which is
+// unavoidable since EigenDA submission needs the complete blob.
+func assembleChunks(ctx context.Context, backend uploadBackend, id string, chunkOffsets []int64) ([]byte, error) {
+	var blob []byte
+	for _, offset := range chunkOffsets {
+		chunk, err := backend.Get(ctx, chunkKey(id, offset))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read chunk at offset %d: %w", offset, err)
+		}
+		blob = append(blob, chunk...)
+	}
+	return blob, nil
+}
+
+// deleteChunks removes every chunk persisted for id, logging but not failing on individual
+// delete errors so that cleanup of the remaining chunks still proceeds.
+func (m *ChunkedUploadManager) deleteChunks(ctx context.Context, id string, chunkOffsets []int64) {
+	for _, offset := range chunkOffsets {
+		if err := m.backend.Delete(ctx, chunkKey(id, offset)); err != nil {
+			m.log.Error("chunked upload: failed to delete chunk", "id", id, "offset", offset, "err", err)
+		}
+	}
+}
+
+// handleList is the admin-facing enumeration endpoint, reporting every in-progress
+// upload's id and committed offset.
+func (m *ChunkedUploadManager) handleList(w http.ResponseWriter) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var b bytes.Buffer
+	for id, upload := range m.uploads {
+		fmt.Fprintf(&b, "%s\t%d\n", id, upload.offset)
+	}
+	w.Header().Set("Content-Type", "text/plain")
+	if _, err := w.Write(b.Bytes()); err != nil {
+		m.log.Error("chunked upload: failed to write listing", "err", err)
+	}
+}
+
+// purgeExpired drops uploads older than the manager's TTL, along with their backing store
+// entries, so abandoned uploads don't accumulate indefinitely.
+func (m *ChunkedUploadManager) purgeExpired() {
+	m.mu.Lock()
+	expired := make(map[string][]int64)
+	for id, upload := range m.uploads {
+		if time.Since(upload.createdAt) > m.ttl {
+			expired[id] = append([]int64(nil), upload.chunkOffsets...)
+			delete(m.uploads, id)
+		}
+	}
+	m.mu.Unlock()
+
+	for id, chunkOffsets := range expired {
+		m.deleteChunks(context.Background(), id, chunkOffsets)
+	}
+}
+
+// chunkKey identifies a single persisted chunk of an upload, namespaced by upload id and
+// the chunk's start offset so that every PATCH writes a distinct key instead of
+// read-modify-writing one growing object.
+func chunkKey(id string, start int64) []byte {
+	return []byte(fmt.Sprintf("%s/%d", id, start))
+}
+
+// parseContentRange parses a "Content-Range: bytes start-end/total" (or "start-end/*")
+// header, returning the inclusive start and end byte offsets.
+func parseContentRange(header string) (start, end int64, err error) {
+	header = strings.TrimPrefix(header, "bytes ")
+	rangeAndSize := strings.SplitN(header, "/", 2)
+	bounds := strings.SplitN(rangeAndSize[0], "-", 2)
+	if len(bounds) != 2 {
+		return 0, 0, fmt.Errorf("malformed Content-Range header: %q", header)
+	}
+
+	start, err = strconv.ParseInt(bounds[0], 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("malformed Content-Range start: %w", err)
+	}
+	end, err = strconv.ParseInt(bounds[1], 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("malformed Content-Range end: %w", err)
+	}
+	return start, end, nil
+}
@@ -0,0 +1,186 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// memoryUploadBackend is a minimal in-memory uploadBackend, standing in for S3Store in
+// tests that only care about chunked-upload bookkeeping, not the backing store itself.
+type memoryUploadBackend struct {
+	mu   sync.Mutex
+	data map[string][]byte
+}
+
+func newMemoryUploadBackend() *memoryUploadBackend {
+	return &memoryUploadBackend{data: make(map[string][]byte)}
+}
+
+func (b *memoryUploadBackend) Get(_ context.Context, key []byte) ([]byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	v, ok := b.data[string(key)]
+	if !ok {
+		return nil, fmt.Errorf("no such key: %s", key)
+	}
+	return append([]byte(nil), v...), nil
+}
+
+func (b *memoryUploadBackend) Put(_ context.Context, key []byte, value []byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.data[string(key)] = append([]byte(nil), value...)
+	return nil
+}
+
+func (b *memoryUploadBackend) Delete(_ context.Context, key []byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.data, string(key))
+	return nil
+}
+
+func newTestUploadManager() (*ChunkedUploadManager, *memoryUploadBackend) {
+	backend := newMemoryUploadBackend()
+	m := NewChunkedUploadManager(nil, backend, time.Hour, log.New())
+	return m, backend
+}
+
+func appendChunk(t *testing.T, m *ChunkedUploadManager, id string, start int, chunk []byte) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPatch, UploadsRoute+id, bytes.NewReader(chunk))
+	req.Header.Set("Content-Range", fmt.Sprintf("%d-%d", start, start+len(chunk)-1))
+	w := httptest.NewRecorder()
+	m.handleAppend(w, req, id)
+	return w
+}
+
+func TestChunkedUpload_OffsetTracking(t *testing.T) {
+	m, backend := newTestUploadManager()
+
+	w := httptest.NewRecorder()
+	m.handleCreate(w, httptest.NewRequest(http.MethodPost, UploadsRoute, nil))
+	id := w.Header().Get("Docker-Upload-UUID")
+	if id == "" {
+		t.Fatal("expected handleCreate to assign an upload id")
+	}
+
+	first := appendChunk(t, m, id, 0, []byte("hello "))
+	if first.Code != http.StatusAccepted {
+		t.Fatalf("expected 202 for first chunk, got %d", first.Code)
+	}
+	if got := first.Header().Get("Range"); got != "0-6" {
+		t.Fatalf("expected Range 0-6 after first chunk, got %q", got)
+	}
+
+	second := appendChunk(t, m, id, 6, []byte("world"))
+	if second.Code != http.StatusAccepted {
+		t.Fatalf("expected 202 for second chunk, got %d", second.Code)
+	}
+	if got := second.Header().Get("Range"); got != "0-11" {
+		t.Fatalf("expected Range 0-11 after second chunk, got %q", got)
+	}
+
+	firstChunk, err := backend.Get(context.Background(), chunkKey(id, 0))
+	if err != nil {
+		t.Fatalf("expected first chunk to be persisted under its own key: %v", err)
+	}
+	if !bytes.Equal(firstChunk, []byte("hello ")) {
+		t.Fatalf("expected first chunk %q, got %q", "hello ", firstChunk)
+	}
+	secondChunk, err := backend.Get(context.Background(), chunkKey(id, 6))
+	if err != nil {
+		t.Fatalf("expected second chunk to be persisted under its own key: %v", err)
+	}
+	if !bytes.Equal(secondChunk, []byte("world")) {
+		t.Fatalf("expected second chunk %q, got %q", "world", secondChunk)
+	}
+
+	assembled, err := assembleChunks(context.Background(), backend, id, []int64{0, 6})
+	if err != nil {
+		t.Fatalf("assembleChunks failed: %v", err)
+	}
+	if !bytes.Equal(assembled, []byte("hello world")) {
+		t.Fatalf("expected assembled upload %q, got %q", "hello world", assembled)
+	}
+
+	statusW := httptest.NewRecorder()
+	m.handleStatus(statusW, id)
+	if got := statusW.Header().Get("Range"); got != "0-11" {
+		t.Fatalf("expected handleStatus to report Range 0-11, got %q", got)
+	}
+}
+
+func TestChunkedUpload_RejectsOutOfOrderChunk(t *testing.T) {
+	m, backend := newTestUploadManager()
+
+	w := httptest.NewRecorder()
+	m.handleCreate(w, httptest.NewRequest(http.MethodPost, UploadsRoute, nil))
+	id := w.Header().Get("Docker-Upload-UUID")
+
+	first := appendChunk(t, m, id, 0, []byte("hello "))
+	if first.Code != http.StatusAccepted {
+		t.Fatalf("expected 202 for first chunk, got %d", first.Code)
+	}
+
+	// skip ahead instead of continuing at offset 6.
+	skipped := appendChunk(t, m, id, 20, []byte("world"))
+	if skipped.Code != http.StatusRequestedRangeNotSatisfiable {
+		t.Fatalf("expected 416 for an out-of-order chunk, got %d", skipped.Code)
+	}
+	if got := skipped.Header().Get("Range"); got != "0-6" {
+		t.Fatalf("expected 416 response to report the current committed Range 0-6, got %q", got)
+	}
+
+	// the upload's offset and persisted chunk must be unchanged by the rejected chunk, and
+	// no chunk should have been written at the rejected (wrong) offset.
+	stored, err := backend.Get(context.Background(), chunkKey(id, 0))
+	if err != nil {
+		t.Fatalf("expected prior chunk to remain persisted: %v", err)
+	}
+	if !bytes.Equal(stored, []byte("hello ")) {
+		t.Fatalf("expected stored chunk to be unchanged by rejected chunk, got %q", stored)
+	}
+	if _, err := backend.Get(context.Background(), chunkKey(id, 20)); err == nil {
+		t.Fatal("expected no chunk to be persisted at the rejected out-of-order offset")
+	}
+
+	retry := appendChunk(t, m, id, 6, []byte("world"))
+	if retry.Code != http.StatusAccepted {
+		t.Fatalf("expected a correctly-offset retry to succeed, got %d", retry.Code)
+	}
+}
+
+func TestChunkedUpload_UnknownUploadID(t *testing.T) {
+	m, _ := newTestUploadManager()
+
+	w := appendChunk(t, m, "does-not-exist", 0, []byte("data"))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for an unknown upload id, got %d", w.Code)
+	}
+}
+
+func TestParseContentRange(t *testing.T) {
+	start, end, err := parseContentRange("0-10")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if start != 0 || end != 10 {
+		t.Fatalf("expected (0, 10), got (%d, %d)", start, end)
+	}
+
+	if _, _, err := parseContentRange("not-a-range"); err == nil {
+		t.Fatal("expected an error for a malformed Content-Range header")
+	}
+	if _, _, err := parseContentRange("bytes 0-10/20"); err != nil {
+		t.Fatalf("expected the bytes-prefixed form to parse, got: %v", err)
+	}
+}
@@ -0,0 +1,306 @@
+package server
+
+import (
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/Layr-Labs/eigenda-proxy/commitments"
+	"github.com/Layr-Labs/eigenda-proxy/store"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/google/uuid"
+)
+
+// S3Route is registered as a catch-all so that the gateway can dispatch on method and
+// query parameters (?uploads, ?uploadId=...) the way the S3 REST API does.
+const S3Route = "/s3/"
+
+// s3Gateway exposes an S3-compatible REST API in front of an IRouter, so that unmodified
+// S3 SDKs (aws-sdk-go, boto3, minio-go) can read and write commitments without knowing
+// about the eigenda-proxy wire format. Object keys are the hex encoding of the commitment.
+type s3Gateway struct {
+	log    log.Logger
+	router store.IRouter
+
+	mu        sync.Mutex
+	multipart map[string]*multipartUpload
+}
+
+// multipartUpload buffers the parts of an in-progress CreateMultipartUpload until
+// CompleteMultipartUpload assembles and submits them as a single blob.
+type multipartUpload struct {
+	bucket string
+	key    string
+	parts  map[int][]byte
+}
+
+func newS3Gateway(router store.IRouter, log log.Logger) *s3Gateway {
+	return &s3Gateway{
+		log:       log,
+		router:    router,
+		multipart: make(map[string]*multipartUpload),
+	}
+}
+
+// listBucketResult mirrors the subset of the ListObjectsV2 response shape that a
+// commitment-addressed store, which has no notion of hierarchical keys, can populate.
+type listBucketResult struct {
+	XMLName xml.Name `xml:"ListBucketResult"`
+	Name    string   `xml:"Name"`
+	KeyCount int     `xml:"KeyCount"`
+}
+
+func (g *s3Gateway) Handle(w http.ResponseWriter, r *http.Request) {
+	bucket, key := splitBucketKey(strings.TrimPrefix(r.URL.Path, S3Route))
+	query := r.URL.Query()
+
+	switch {
+	case r.Method == http.MethodGet && key == "" && query.Get("list-type") == "2":
+		g.handleListObjectsV2(w, bucket)
+	case r.Method == http.MethodPost && query.Has("uploads"):
+		g.handleCreateMultipartUpload(w, bucket, key)
+	case r.Method == http.MethodPut && query.Has("uploadId") && query.Has("partNumber"):
+		g.handleUploadPart(w, r, query.Get("uploadId"), query.Get("partNumber"))
+	case r.Method == http.MethodPost && query.Has("uploadId"):
+		g.handleCompleteMultipartUpload(w, r, bucket, key, query.Get("uploadId"))
+	case r.Method == http.MethodPut:
+		g.handlePutObject(w, r, key)
+	case r.Method == http.MethodGet:
+		g.handleGetObject(w, r, key)
+	case r.Method == http.MethodHead:
+		g.handleHeadObject(w, r, key)
+	case r.Method == http.MethodDelete:
+		g.handleDeleteObject(w, key)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func splitBucketKey(path string) (bucket, key string) {
+	path = strings.TrimPrefix(path, "/")
+	parts := strings.SplitN(path, "/", 2)
+	bucket = parts[0]
+	if len(parts) == 2 {
+		key = parts[1]
+	}
+	return bucket, key
+}
+
+func (g *s3Gateway) handleListObjectsV2(w http.ResponseWriter, bucket string) {
+	// the underlying store is addressed purely by commitment, so there is no notion of
+	// enumerating all keys in a bucket; report an empty listing rather than erroring.
+	result := listBucketResult{Name: bucket, KeyCount: 0}
+	w.Header().Set("Content-Type", "application/xml")
+	if err := xml.NewEncoder(w).Encode(result); err != nil {
+		g.log.Error("failed to encode ListObjectsV2 response", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+func (g *s3Gateway) handleGetObject(w http.ResponseWriter, r *http.Request, key string) {
+	comm, err := hex.DecodeString(strings.TrimPrefix(key, "0x"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	data, err := g.router.Get(r.Context(), comm, commitments.SimpleCommitmentMode)
+	if err != nil && errors.Is(err, ErrNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		g.log.Error("s3 gateway: failed to fetch object", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	g.log.Info(fmt.Sprintf("s3 gateway: read object %s\n", key), identityLogFields(r.Context())...)
+	w.Header().Set("x-amz-meta-commitment", key)
+	if _, err := w.Write(data); err != nil {
+		g.log.Error("s3 gateway: failed to write response", "err", err)
+	}
+}
+
+func (g *s3Gateway) handleHeadObject(w http.ResponseWriter, r *http.Request, key string) {
+	comm, err := hex.DecodeString(strings.TrimPrefix(key, "0x"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	data, err := g.router.Get(r.Context(), comm, commitments.SimpleCommitmentMode)
+	if err != nil && errors.Is(err, ErrNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.Header().Set("x-amz-meta-commitment", key)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (g *s3Gateway) handlePutObject(w http.ResponseWriter, r *http.Request, key string) {
+	var comm []byte
+	if key != "" {
+		decoded, err := hex.DecodeString(strings.TrimPrefix(key, "0x"))
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		comm = decoded
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		g.log.Error("s3 gateway: failed to read request body", "err", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	commitment, err := g.router.Put(r.Context(), commitments.SimpleCommitmentMode, comm, body)
+	if err != nil {
+		g.log.Error("s3 gateway: failed to put object", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	g.log.Info(fmt.Sprintf("s3 gateway: write object, commitment %x\n", commitment), identityLogFields(r.Context())...)
+	w.Header().Set("x-amz-meta-commitment", hex.EncodeToString(commitment))
+	w.WriteHeader(http.StatusOK)
+}
+
+func (g *s3Gateway) handleDeleteObject(w http.ResponseWriter, _ string) {
+	// commitments are content-addressed and immutable once written; DELETE is accepted
+	// for S3-SDK compatibility but the underlying blob is not removable by this API.
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (g *s3Gateway) handleCreateMultipartUpload(w http.ResponseWriter, bucket, key string) {
+	uploadID := uuid.New().String()
+
+	g.mu.Lock()
+	g.multipart[uploadID] = &multipartUpload{bucket: bucket, key: key, parts: make(map[int][]byte)}
+	g.mu.Unlock()
+
+	type initiateMultipartUploadResult struct {
+		XMLName  xml.Name `xml:"InitiateMultipartUploadResult"`
+		Bucket   string   `xml:"Bucket"`
+		Key      string   `xml:"Key"`
+		UploadID string   `xml:"UploadId"`
+	}
+
+	w.Header().Set("Content-Type", "application/xml")
+	if err := xml.NewEncoder(w).Encode(initiateMultipartUploadResult{
+		Bucket: bucket, Key: key, UploadID: uploadID,
+	}); err != nil {
+		g.log.Error("failed to encode CreateMultipartUpload response", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+func (g *s3Gateway) handleUploadPart(w http.ResponseWriter, r *http.Request, uploadID, partNumberStr string) {
+	partNumber, err := strconv.Atoi(partNumberStr)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	g.mu.Lock()
+	upload, ok := g.multipart[uploadID]
+	g.mu.Unlock()
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		g.log.Error("s3 gateway: failed to read part body", "err", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	g.mu.Lock()
+	upload.parts[partNumber] = body
+	g.mu.Unlock()
+
+	partHash := crypto.Keccak256(body)
+	w.Header().Set("ETag", fmt.Sprintf("%q", hex.EncodeToString(partHash)))
+	w.WriteHeader(http.StatusOK)
+}
+
+func (g *s3Gateway) handleCompleteMultipartUpload(w http.ResponseWriter, r *http.Request, bucket, key, uploadID string) {
+	g.mu.Lock()
+	upload, ok := g.multipart[uploadID]
+	if ok {
+		delete(g.multipart, uploadID)
+	}
+	g.mu.Unlock()
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	assembled := assembleParts(upload.parts)
+
+	var comm []byte
+	if key != "" {
+		if decoded, err := hex.DecodeString(strings.TrimPrefix(key, "0x")); err == nil {
+			comm = decoded
+		}
+	}
+
+	commitment, err := g.router.Put(r.Context(), commitments.SimpleCommitmentMode, comm, assembled)
+	if err != nil {
+		g.log.Error("s3 gateway: failed to submit assembled multipart upload", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	type completeMultipartUploadResult struct {
+		XMLName xml.Name `xml:"CompleteMultipartUploadResult"`
+		Bucket  string   `xml:"Bucket"`
+		Key     string   `xml:"Key"`
+		ETag    string   `xml:"ETag"`
+	}
+
+	g.log.Info(fmt.Sprintf("s3 gateway: write multipart object, commitment %x\n", commitment), identityLogFields(r.Context())...)
+	w.Header().Set("x-amz-meta-commitment", hex.EncodeToString(commitment))
+	w.Header().Set("Content-Type", "application/xml")
+	if err := xml.NewEncoder(w).Encode(completeMultipartUploadResult{
+		Bucket: bucket, Key: key, ETag: hex.EncodeToString(commitment),
+	}); err != nil {
+		g.log.Error("failed to encode CompleteMultipartUpload response", "err", err)
+	}
+}
+
+// assembleParts concatenates a multipart upload's parts in ascending part-number order.
+func assembleParts(parts map[int][]byte) []byte {
+	numbers := make([]int, 0, len(parts))
+	for n := range parts {
+		numbers = append(numbers, n)
+	}
+	for i := 1; i < len(numbers); i++ {
+		for j := i; j > 0 && numbers[j-1] > numbers[j]; j-- {
+			numbers[j-1], numbers[j] = numbers[j], numbers[j-1]
+		}
+	}
+
+	var out []byte
+	for _, n := range numbers {
+		out = append(out, parts[n]...)
+	}
+	return out
+}
@@ -12,11 +12,15 @@ import (
 	"strings"
 	"time"
 
+	"github.com/Layr-Labs/eigenda-proxy/auth"
 	"github.com/Layr-Labs/eigenda-proxy/commitments"
 	"github.com/Layr-Labs/eigenda-proxy/metrics"
 	"github.com/Layr-Labs/eigenda-proxy/store"
+	"github.com/Layr-Labs/eigenda-proxy/verify"
 	"github.com/ethereum-optimism/optimism/op-service/rpc"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/log"
 )
 
@@ -33,6 +37,11 @@ const (
 
 	DomainFilterKey   = "domain"
 	CommitmentModeKey = "commitment_mode"
+
+	// VerifyQueryKey, when set to "true", requests that HandleGet verify the blob's
+	// merkle inclusion proof against its cert before responding, even if VerifyGets is
+	// not enabled server-wide.
+	VerifyQueryKey = "verify"
 )
 
 type Server struct {
@@ -41,8 +50,14 @@ type Server struct {
 	router     store.IRouter
 	m          metrics.Metricer
 	tls        *rpc.ServerTLSConfig
+	auth       *auth.Middleware
+	s3         *s3Gateway
+	uploads    *ChunkedUploadManager
 	httpServer *http.Server
 	listener   net.Listener
+	// VerifyGets, when true, makes HandleGet verify every blob's merkle inclusion
+	// proof against its claimed root before responding, regardless of VerifyQueryKey.
+	VerifyGets bool
 }
 
 type ServiceResult struct {
@@ -82,6 +97,56 @@ func WithMetrics(handleFn func(http.ResponseWriter, *http.Request) (ServiceResul
 	}
 }
 
+// namespaceForPath derives the ACL namespace to check for path: the literal commitment key,
+// or "" for a keyless PUT (path.Base == "put"), whose commitment doesn't exist until after
+// the blob is written and so can't be namespace-restricted in advance. This mirrors
+// HandlePut's own key != "put" special case for commitment decoding.
+func namespaceForPath(p string) string {
+	key := path.Base(p)
+	if key == "put" {
+		return ""
+	}
+	return key
+}
+
+// WithAuthentication is a middleware that enforces AWS SigV4 authentication and ACL checks
+// via m before invoking handleFn. If m is nil, requests pass through unauthenticated.
+func WithAuthentication(handleFn func(http.ResponseWriter, *http.Request) (ServiceResult, error),
+	perm auth.Permission, m *auth.Middleware) func(http.ResponseWriter, *http.Request) (ServiceResult, error) {
+	return func(w http.ResponseWriter, r *http.Request) (ServiceResult, error) {
+		if m == nil {
+			return handleFn(w, r)
+		}
+
+		result, err := m.Authenticate(r, perm, namespaceForPath(r.URL.Path))
+		if err != nil {
+			status := auth.StatusForError(err)
+			w.WriteHeader(status)
+			return ServiceResult{}, fmt.Errorf("authentication failed: %w", err)
+		}
+
+		r = r.WithContext(auth.WithIdentity(r.Context(), result))
+		return handleFn(w, r)
+	}
+}
+
+// authorizeRaw enforces svr.auth (if configured) for handlers that don't fit the
+// ServiceResult-returning shape WithAuthentication wraps, namely the S3 gateway and
+// chunked-upload routes. On success it returns a request carrying the authenticated
+// identity; on failure it has already written the appropriate status to w and the caller
+// must not proceed.
+func (svr *Server) authorizeRaw(w http.ResponseWriter, r *http.Request, perm auth.Permission, namespace string) (*http.Request, bool) {
+	if svr.auth == nil {
+		return r, true
+	}
+	result, err := svr.auth.Authenticate(r, perm, namespace)
+	if err != nil {
+		w.WriteHeader(auth.StatusForError(err))
+		return r, false
+	}
+	return r.WithContext(auth.WithIdentity(r.Context(), result)), true
+}
+
 // WithLogging is a middleware that logs the request method and URL.
 func WithLogging(handleFn func(http.ResponseWriter, *http.Request) error,
 	log log.Logger) func(http.ResponseWriter, *http.Request) {
@@ -98,10 +163,51 @@ func WithLogging(handleFn func(http.ResponseWriter, *http.Request) error,
 func (svr *Server) Start() error {
 	mux := http.NewServeMux()
 
-	mux.HandleFunc(GetRoute, WithLogging(WithMetrics(svr.HandleGet, svr.m), svr.log))
-	mux.HandleFunc(PutRoute, WithLogging(WithMetrics(svr.HandlePut, svr.m), svr.log))
+	mux.HandleFunc(GetRoute, WithLogging(WithMetrics(WithAuthentication(svr.HandleGet, auth.PermissionRead, svr.auth), svr.m), svr.log))
+	mux.HandleFunc(PutRoute, WithLogging(WithMetrics(WithAuthentication(svr.HandlePut, auth.PermissionWrite, svr.auth), svr.m), svr.log))
 	mux.HandleFunc("/health", WithLogging(svr.Health, svr.log))
 
+	if svr.s3 != nil {
+		mux.HandleFunc(S3Route, WithLogging(func(w http.ResponseWriter, r *http.Request) error {
+			perm := auth.PermissionRead
+			if r.Method != http.MethodGet && r.Method != http.MethodHead {
+				perm = auth.PermissionWrite
+			}
+			// the object key is the hex-encoded commitment, so it is checked against the
+			// identity's namespaces; a request without a key (a listing, or a keyless PUT)
+			// passes the namespace check with "", the same way a keyless /put/ does.
+			_, key := splitBucketKey(strings.TrimPrefix(r.URL.Path, S3Route))
+			r, ok := svr.authorizeRaw(w, r, perm, key)
+			if !ok {
+				return nil
+			}
+			svr.s3.Handle(w, r)
+			return nil
+		}, svr.log))
+	}
+
+	if svr.uploads != nil {
+		mux.HandleFunc(UploadsRoute, WithLogging(func(w http.ResponseWriter, r *http.Request) error {
+			perm := auth.PermissionWrite
+			switch {
+			case r.Method == http.MethodDelete, r.Method == http.MethodGet && r.URL.Path == UploadsRoute:
+				// canceling and listing uploads are the admin-facing endpoints.
+				perm = auth.PermissionAdmin
+			case r.Method == http.MethodGet:
+				perm = auth.PermissionRead
+			}
+			// the upload id is a random UUID, not a commitment namespace to check ACLs
+			// against; the upload's eventual commitment doesn't exist until handleFinalize,
+			// the same way a keyless PUT's doesn't.
+			r, ok := svr.authorizeRaw(w, r, perm, "")
+			if !ok {
+				return nil
+			}
+			svr.uploads.Handle(w, r)
+			return nil
+		}, svr.log))
+	}
+
 	svr.httpServer.Handler = mux
 
 	listener, err := net.Listen("tcp", svr.endpoint)
@@ -138,6 +244,31 @@ func (svr *Server) Start() error {
 	}
 }
 
+// WithAuth attaches an authentication middleware to the server, wiring the server's
+// metrics recorder into it so that auth decisions and impersonations are recorded
+// alongside request metrics. When unset, the server accepts all requests unauthenticated,
+// preserving the prior behavior.
+func (svr *Server) WithAuth(m *auth.Middleware) *Server {
+	svr.auth = m.WithMetrics(svr.m)
+	return svr
+}
+
+// WithS3Gateway enables the S3-compatible REST gateway, mounted at S3Route, allowing any
+// S3 SDK to read and write commitments via the standard bucket/key object model.
+func (svr *Server) WithS3Gateway() *Server {
+	svr.s3 = newS3Gateway(svr.router, svr.log)
+	return svr
+}
+
+// WithChunkedUploads enables the resumable/chunked PUT protocol at UploadsRoute, letting
+// clients stream large blobs in pieces that are persisted as they arrive, instead of
+// buffering the whole body in one request; the complete blob is assembled in memory only
+// at finalization.
+func (svr *Server) WithChunkedUploads(ttl time.Duration) *Server {
+	svr.uploads = NewChunkedUploadManager(svr.router, svr.router.GetS3Store(), ttl, svr.log)
+	return svr
+}
+
 func (svr *Server) Endpoint() string {
 	return svr.listener.Addr().String()
 }
@@ -181,10 +312,50 @@ func (svr *Server) HandleGet(w http.ResponseWriter, r *http.Request) (ServiceRes
 		return ServiceResult{meta: meta}, err
 	}
 
+	if svr.VerifyGets || r.URL.Query().Get(VerifyQueryKey) == "true" {
+		if err := svr.verifyInclusionProof(r.Context(), comm, meta.Mode, input); err != nil {
+			svr.log.Info("inclusion proof verification failed", "err", err)
+			w.WriteHeader(http.StatusBadGateway)
+			return ServiceResult{meta: meta}, fmt.Errorf("inclusion proof verification failed: %w", err)
+		}
+	}
+
+	svr.log.Info(fmt.Sprintf("read commitment: %x\n", comm), identityLogFields(r.Context())...)
 	svr.WriteResponse(w, input)
 	return ServiceResult{meta: meta, bodyLength: uint(len(input))}, nil
 }
 
+// verifyInclusionProof fetches comm's cert from the router — the source of truth for what
+// root the blob was actually confirmed under — and checks that recomputing the root from
+// keccak256(blob) and the cert's proof matches the cert's root. The proof/root/index are
+// never taken from the request: a client that tampered with the blob also controls
+// anything it could supply alongside it, which would make verification a no-op.
+func (svr *Server) verifyInclusionProof(ctx context.Context, comm []byte, mode commitments.CommitmentMode, blob []byte) error {
+	provider, ok := svr.router.(store.CertProvider)
+	if !ok {
+		return errors.New("router does not support inclusion proof verification")
+	}
+
+	cert, err := provider.GetCertInclusionData(ctx, comm, mode)
+	if err != nil {
+		return fmt.Errorf("failed to fetch cert inclusion data: %w", err)
+	}
+
+	hashFn := verify.Keccak256Hasher
+	if cert.HashAlgo == "sha256" {
+		hashFn = verify.SHA256Hasher
+	}
+
+	computed, err := verify.ProcessInclusionProofWithHasher(cert.Proof, crypto.Keccak256Hash(blob), cert.Index, hashFn)
+	if err != nil {
+		return fmt.Errorf("failed to process inclusion proof: %w", err)
+	}
+	if computed != common.BytesToHash(cert.Root) {
+		return fmt.Errorf("computed root %s does not match cert root %s", computed, common.BytesToHash(cert.Root))
+	}
+	return nil
+}
+
 func (svr *Server) HandlePut(w http.ResponseWriter, r *http.Request) (ServiceResult, error) {
 	meta, err := ReadCommitmentMeta(r)
 	if err != nil {
@@ -224,12 +395,23 @@ func (svr *Server) HandlePut(w http.ResponseWriter, r *http.Request) (ServiceRes
 		return ServiceResult{meta: meta}, err
 	}
 
-	svr.log.Info(fmt.Sprintf("write commitment: %x\n", comm))
+	svr.log.Info(fmt.Sprintf("write commitment: %x\n", comm), identityLogFields(r.Context())...)
 	// write out encoded commitment
 	svr.WriteResponse(w, responseCommit)
 	return ServiceResult{meta: meta, bodyLength: uint(len(input))}, nil
 }
 
+// identityLogFields returns "real"/"effective" key-value log fields for the identity that
+// authenticated ctx's request, if any, so that impersonated requests can be traced back to
+// both the signing credential and the identity the request was evaluated against.
+func identityLogFields(ctx context.Context) []interface{} {
+	result, ok := auth.IdentityFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	return []interface{}{"real", result.Real.AccessKeyID, "effective", result.Effective.AccessKeyID}
+}
+
 func (svr *Server) WriteResponse(w http.ResponseWriter, data []byte) {
 	if _, err := w.Write(data); err != nil {
 		svr.WriteInternalError(w, err)
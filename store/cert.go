@@ -0,0 +1,27 @@
+package store
+
+import (
+	"context"
+
+	"github.com/Layr-Labs/eigenda-proxy/commitments"
+)
+
+// CertInclusionData is the subset of an EigenDA cert needed to verify a blob's merkle
+// inclusion proof against the batch root: the sibling hashes, the blob's leaf index, and
+// the root the batch was confirmed under.
+type CertInclusionData struct {
+	Proof []byte
+	Root  []byte
+	Index uint64
+	// HashAlgo names the hash used to combine proof siblings: "keccak256" (default) or
+	// "sha256".
+	HashAlgo string
+}
+
+// CertProvider is implemented by routers that can return inclusion-proof data for a
+// commitment's cert, alongside the blob itself. It is checked via a type assertion in the
+// GET path rather than added to IRouter directly, so routers that don't carry certs (e.g.
+// a pure S3 passthrough) aren't forced to implement it.
+type CertProvider interface {
+	GetCertInclusionData(ctx context.Context, comm []byte, mode commitments.CommitmentMode) (CertInclusionData, error)
+}
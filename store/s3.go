@@ -5,20 +5,33 @@ import (
 	"context"
 	"encoding/hex"
 	"errors"
+	"fmt"
 	"io"
+	"net/url"
 	"path"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/smithy-go"
+	smithyendpoints "github.com/aws/smithy-go/endpoints"
 	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/minio/minio-go/v7"
-
-	"github.com/minio/minio-go/v7/pkg/credentials"
 )
 
 const (
 	S3CredentialStatic  S3CredentialType = "static"
 	S3CredentialIAM     S3CredentialType = "iam"
 	S3CredentialUnknown S3CredentialType = "unknown"
+
+	// maxRetries bounds the SDK's built-in exponential backoff retrier for transient
+	// network errors and 5xx/throttling responses.
+	maxRetries = 5
 )
 
 func StringToS3CredentialType(s string) S3CredentialType {
@@ -35,36 +48,63 @@ func StringToS3CredentialType(s string) S3CredentialType {
 var _ PrecomputedKeyStore = (*S3Store)(nil)
 
 type S3CredentialType string
+
+// ServerSideEncryption configures per-object SSE. Empty values disable SSE.
+type ServerSideEncryption struct {
+	// Mode is one of "" (disabled), "AES256" (SSE-S3) or "aws:kms" (SSE-KMS).
+	Mode string
+	// KMSKeyID is sent when Mode is "aws:kms" and it is non-empty; otherwise ignored.
+	KMSKeyID string
+}
+
 type S3Config struct {
 	S3CredentialType S3CredentialType
 	Bucket           string
 	Path             string
 	Endpoint         string
+	Region           string
 	AccessKeyID      string
 	AccessKeySecret  string
-	Profiling        bool
-	Backup           bool
-	Timeout          time.Duration
+	// RoleARN, if set, causes credentials to be derived via STS AssumeRole, calling STS
+	// with the base credential chain's credentials, rather than used directly.
+	RoleARN string
+	// UsePathStyle selects path-style addressing (endpoint/bucket/key) instead of the
+	// default virtual-hosted style (bucket.endpoint/key); required by most MinIO/Ceph
+	// deployments.
+	UsePathStyle bool
+	// DisableTLS toggles plaintext HTTP against the endpoint, for local MinIO/Ceph setups.
+	DisableTLS bool
+	Encryption ServerSideEncryption
+	Profiling  bool
+	Backup     bool
+	Timeout    time.Duration
 }
 
 type S3Store struct {
-	cfg    S3Config
-	client *minio.Client
-	stats  *Stats
+	cfg      S3Config
+	client   *s3.Client
+	uploader *manager.Uploader
+	stats    *Stats
 }
 
 func NewS3(cfg S3Config) (*S3Store, error) {
-	client, err := minio.New(cfg.Endpoint, &minio.Options{
-		Creds:  creds(cfg),
-		Secure: false,
-	})
+	awsCfg, err := loadAWSConfig(cfg)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
 	}
 
+	client := s3.NewFromConfig(awsCfg, func(o *s3.Options) {
+		o.UsePathStyle = cfg.UsePathStyle
+		o.RetryMaxAttempts = maxRetries
+		if cfg.Endpoint != "" {
+			o.EndpointResolverV2 = staticEndpointResolver{endpoint: cfg.Endpoint, disableTLS: cfg.DisableTLS}
+		}
+	})
+
 	return &S3Store{
-		cfg:    cfg,
-		client: client,
+		cfg:      cfg,
+		client:   client,
+		uploader: manager.NewUploader(client),
 		stats: &Stats{
 			Entries: 0,
 			Reads:   0,
@@ -72,17 +112,53 @@ func NewS3(cfg S3Config) (*S3Store, error) {
 	}, nil
 }
 
+// loadAWSConfig builds an aws.Config using the modern credential provider chain: explicit
+// static keys if configured, falling back to the default chain (env vars, shared config,
+// EC2/ECS IMDS, IRSA/web-identity), optionally layered with an STS AssumeRole provider
+// when RoleARN is set.
+func loadAWSConfig(cfg S3Config) (aws.Config, error) {
+	opts := []func(*awsconfig.LoadOptions) error{}
+	if cfg.Region != "" {
+		opts = append(opts, awsconfig.WithRegion(cfg.Region))
+	}
+
+	if cfg.S3CredentialType == S3CredentialStatic {
+		opts = append(opts, awsconfig.WithCredentialsProvider(
+			credentials.NewStaticCredentialsProvider(cfg.AccessKeyID, cfg.AccessKeySecret, "")))
+	}
+	// for S3CredentialIAM (and unknown), leave the credentials provider unset so the SDK's
+	// default chain applies: env vars, shared config/credentials file, EC2/ECS IMDS, and
+	// IRSA/web-identity. Hardcoding ec2rolecreds here would resolve EC2 instance-metadata
+	// credentials only, breaking ECS task-role and IRSA deployments that have no EC2 IMDS.
+
+	awsCfg, err := awsconfig.LoadDefaultConfig(context.Background(), opts...)
+	if err != nil {
+		return aws.Config{}, err
+	}
+
+	if cfg.RoleARN != "" {
+		stsClient := sts.NewFromConfig(awsCfg)
+		awsCfg.Credentials = stscreds.NewAssumeRoleProvider(stsClient, cfg.RoleARN)
+	}
+
+	return awsCfg, nil
+}
+
 func (s *S3Store) Get(ctx context.Context, key []byte) ([]byte, error) {
-	result, err := s.client.GetObject(ctx, s.cfg.Bucket, path.Join(s.cfg.Path, hex.EncodeToString(key)), minio.GetObjectOptions{})
+	result, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: &s.cfg.Bucket,
+		Key:    s.objectKey(key),
+	})
 	if err != nil {
-		errResponse := minio.ToErrorResponse(err)
-		if errResponse.Code == "NoSuchKey" {
+		var apiErr smithy.APIError
+		if errors.As(err, &apiErr) && apiErr.ErrorCode() == "NoSuchKey" {
 			return nil, errors.New("value not found in s3 bucket")
 		}
 		return nil, err
 	}
-	defer result.Close()
-	data, err := io.ReadAll(result)
+	defer result.Body.Close()
+
+	data, err := io.ReadAll(result.Body)
 	if err != nil {
 		return nil, err
 	}
@@ -94,9 +170,17 @@ func (s *S3Store) Get(ctx context.Context, key []byte) ([]byte, error) {
 	return data, nil
 }
 
+// Put streams value to S3 via the multipart upload manager, which chunks large payloads
+// and retries failed parts rather than requiring the whole object to fit in one request.
 func (s *S3Store) Put(ctx context.Context, key []byte, value []byte) error {
-	_, err := s.client.PutObject(ctx, s.cfg.Bucket, path.Join(s.cfg.Path, hex.EncodeToString(key)), bytes.NewReader(value), int64(len(value)), minio.PutObjectOptions{})
-	if err != nil {
+	input := &s3.PutObjectInput{
+		Bucket: &s.cfg.Bucket,
+		Key:    s.objectKey(key),
+		Body:   bytes.NewReader(value),
+	}
+	s.applyEncryption(input)
+
+	if _, err := s.uploader.Upload(ctx, input); err != nil {
 		return err
 	}
 
@@ -107,6 +191,15 @@ func (s *S3Store) Put(ctx context.Context, key []byte, value []byte) error {
 	return nil
 }
 
+// Delete removes the object for key from the bucket, for administrative pruning.
+func (s *S3Store) Delete(ctx context.Context, key []byte) error {
+	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: &s.cfg.Bucket,
+		Key:    s.objectKey(key),
+	})
+	return err
+}
+
 func (s *S3Store) Verify(key []byte, value []byte) error {
 	h := crypto.Keccak256Hash(value)
 	if !bytes.Equal(h[:], key) {
@@ -124,9 +217,38 @@ func (s *S3Store) BackendType() BackendType {
 	return S3
 }
 
-func creds(cfg S3Config) *credentials.Credentials {
-	if cfg.S3CredentialType == S3CredentialIAM {
-		return credentials.NewIAM("")
+func (s *S3Store) objectKey(key []byte) *string {
+	k := path.Join(s.cfg.Path, hex.EncodeToString(key))
+	return &k
+}
+
+func (s *S3Store) applyEncryption(input *s3.PutObjectInput) {
+	switch s.cfg.Encryption.Mode {
+	case "AES256":
+		input.ServerSideEncryption = types.ServerSideEncryptionAes256
+	case "aws:kms":
+		input.ServerSideEncryption = types.ServerSideEncryptionAwsKms
+		if s.cfg.Encryption.KMSKeyID != "" {
+			input.SSEKMSKeyId = &s.cfg.Encryption.KMSKeyID
+		}
+	}
+}
+
+// staticEndpointResolver resolves every S3 request to a single custom endpoint, as
+// required by MinIO/Ceph or other S3-compatible deployments.
+type staticEndpointResolver struct {
+	endpoint   string
+	disableTLS bool
+}
+
+func (r staticEndpointResolver) ResolveEndpoint(_ context.Context, _ s3.EndpointParameters) (smithyendpoints.Endpoint, error) {
+	scheme := "https"
+	if r.disableTLS {
+		scheme = "http"
+	}
+	u, err := url.Parse(scheme + "://" + r.endpoint)
+	if err != nil {
+		return smithyendpoints.Endpoint{}, fmt.Errorf("failed to parse custom S3 endpoint: %w", err)
 	}
-	return credentials.NewStaticV4(cfg.AccessKeyID, cfg.AccessKeySecret, "")
+	return smithyendpoints.Endpoint{URI: *u}, nil
 }
@@ -1,12 +1,30 @@
 package verify
 
 import (
+	"crypto/sha256"
 	"errors"
+	"fmt"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 )
 
+// HashFunc computes the hash used to combine a node with its sibling at each level of the
+// tree. Keccak256Hasher matches EigenDA's existing certs; SHA256Hasher is offered as an
+// alternative for certs generated against a sha256 tree.
+type HashFunc func(data []byte) common.Hash
+
+// Keccak256Hasher is the default HashFunc, matching the hash EigenDA certs are built with.
+func Keccak256Hasher(data []byte) common.Hash {
+	return crypto.Keccak256Hash(data)
+}
+
+// SHA256Hasher combines nodes with sha256 instead of keccak256.
+func SHA256Hasher(data []byte) common.Hash {
+	sum := sha256.Sum256(data)
+	return common.BytesToHash(sum[:])
+}
+
 // ProcessInclusionProof computes the merkle root hash based on the provided leaf and proof, returning the result.
 // An error is returned if the proof param is malformed.
 //
@@ -14,11 +32,81 @@ import (
 // the proof was well-formed. The hash returned by this method must be compared to the claimed root hash, to
 // determine if the proof is valid.
 func ProcessInclusionProof(proof []byte, leaf common.Hash, index uint64) (common.Hash, error) {
+	return ProcessInclusionProofWithHasher(proof, leaf, index, Keccak256Hasher)
+}
+
+// ProcessInclusionProofWithHasher is ProcessInclusionProof generalized to an arbitrary
+// sibling-combining hash function, for certs built against trees other than keccak256.
+func ProcessInclusionProofWithHasher(proof []byte, leaf common.Hash, index uint64, hash HashFunc) (common.Hash, error) {
+	if len(proof)%32 != 0 {
+		return common.Hash{}, errors.New("proof length should be a multiple of 32 bytes or 256 bits")
+	}
+
+	computedHash := leaf
+	for i := 0; i < len(proof); i += 32 {
+		var proofElement common.Hash
+		copy(proofElement[:], proof[i:i+32])
+
+		var combined []byte
+		if index%2 == 0 { // right
+			combined = append(computedHash.Bytes(), proofElement.Bytes()...)
+		} else { // left
+			combined = append(proofElement.Bytes(), computedHash.Bytes()...)
+		}
+
+		computedHash = hash(combined)
+		index /= 2
+	}
+
+	return computedHash, nil
+}
+
+// LeafProof bundles a single (leaf, index, proof) inclusion claim for batch processing.
+type LeafProof struct {
+	Leaf  common.Hash
+	Index uint64
+	Proof []byte
+}
+
+// memoKey identifies a computed node by its depth in the tree and its index within that
+// level (the "subtree index"), so that certs sharing an ancestor cross-check their computed
+// hash against it instead of trusting it blindly.
+type memoKey struct {
+	depth uint64
+	index uint64
+}
+
+// BatchProcessInclusionProofs computes merkle roots for every cert in certs against a
+// shared HashFunc, cross-checking intermediate hashes keyed by (depth, subtree index). A
+// cert whose path converges on an ancestor already computed by an earlier cert must
+// recompute that ancestor's hash from its own proof and agree with the cached value; a
+// mismatch means the certs disagree about a shared ancestor and is rejected as an error,
+// rather than silently substituting the cached hash. This makes memoization a consistency
+// check across the batch instead of a way to skip verifying any cert's own proof data.
+func BatchProcessInclusionProofs(certs []LeafProof, hash HashFunc) ([]common.Hash, error) {
+	memo := make(map[memoKey]common.Hash)
+	roots := make([]common.Hash, len(certs))
+
+	for i, cert := range certs {
+		root, err := processWithMemo(cert.Proof, cert.Leaf, cert.Index, hash, memo)
+		if err != nil {
+			return nil, fmt.Errorf("cert %d: %w", i, err)
+		}
+		roots[i] = root
+	}
+
+	return roots, nil
+}
+
+func processWithMemo(proof []byte, leaf common.Hash, index uint64, hash HashFunc, memo map[memoKey]common.Hash) (common.Hash, error) {
 	if len(proof)%32 != 0 {
 		return common.Hash{}, errors.New("proof length should be a multiple of 32 bytes or 256 bits")
 	}
 
+	var depth uint64
 	computedHash := leaf
+	memo[memoKey{depth, index}] = computedHash
+
 	for i := 0; i < len(proof); i += 32 {
 		var proofElement common.Hash
 		copy(proofElement[:], proof[i:i+32])
@@ -30,8 +118,19 @@ func ProcessInclusionProof(proof []byte, leaf common.Hash, index uint64) (common
 			combined = append(proofElement.Bytes(), computedHash.Bytes()...)
 		}
 
-		computedHash = crypto.Keccak256Hash(combined)
 		index /= 2
+		depth++
+
+		computedHash = hash(combined)
+		key := memoKey{depth, index}
+		if cached, ok := memo[key]; ok {
+			if cached != computedHash {
+				return common.Hash{}, fmt.Errorf("cert disagrees with a previously verified cert at depth %d, index %d: computed %s, cached %s",
+					depth, index, computedHash, cached)
+			}
+			continue
+		}
+		memo[key] = computedHash
 	}
 
 	return computedHash, nil
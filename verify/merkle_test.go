@@ -0,0 +1,128 @@
+package verify
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// buildFourLeafTree builds a 4-leaf merkle tree over leaves using hash, returning the root
+// alongside a LeafProof for each leaf so tests can exercise real, valid inclusion proofs
+// instead of hand-rolled byte fixtures.
+func buildFourLeafTree(leaves [4]common.Hash, hash HashFunc) (common.Hash, []LeafProof) {
+	combine := func(a, b common.Hash) common.Hash {
+		return hash(append(append([]byte{}, a.Bytes()...), b.Bytes()...))
+	}
+
+	n01 := combine(leaves[0], leaves[1])
+	n23 := combine(leaves[2], leaves[3])
+	root := combine(n01, n23)
+
+	certs := []LeafProof{
+		{Leaf: leaves[0], Index: 0, Proof: append(append([]byte{}, leaves[1].Bytes()...), n23.Bytes()...)},
+		{Leaf: leaves[1], Index: 1, Proof: append(append([]byte{}, leaves[0].Bytes()...), n23.Bytes()...)},
+		{Leaf: leaves[2], Index: 2, Proof: append(append([]byte{}, leaves[3].Bytes()...), n01.Bytes()...)},
+		{Leaf: leaves[3], Index: 3, Proof: append(append([]byte{}, leaves[2].Bytes()...), n01.Bytes()...)},
+	}
+	return root, certs
+}
+
+func TestBatchProcessInclusionProofs_MatchesSinglePath(t *testing.T) {
+	leaves := [4]common.Hash{
+		Keccak256Hasher([]byte("leaf0")),
+		Keccak256Hasher([]byte("leaf1")),
+		Keccak256Hasher([]byte("leaf2")),
+		Keccak256Hasher([]byte("leaf3")),
+	}
+	root, certs := buildFourLeafTree(leaves, Keccak256Hasher)
+
+	batchRoots, err := BatchProcessInclusionProofs(certs, Keccak256Hasher)
+	if err != nil {
+		t.Fatalf("BatchProcessInclusionProofs failed: %v", err)
+	}
+	if len(batchRoots) != len(certs) {
+		t.Fatalf("expected %d roots, got %d", len(certs), len(batchRoots))
+	}
+
+	for i, cert := range certs {
+		singleRoot, err := ProcessInclusionProofWithHasher(cert.Proof, cert.Leaf, cert.Index, Keccak256Hasher)
+		if err != nil {
+			t.Fatalf("cert %d: ProcessInclusionProofWithHasher failed: %v", i, err)
+		}
+		if singleRoot != root {
+			t.Fatalf("cert %d: single-path root %s does not match expected tree root %s", i, singleRoot, root)
+		}
+		if batchRoots[i] != singleRoot {
+			t.Fatalf("cert %d: batch root %s does not match single-path root %s", i, batchRoots[i], singleRoot)
+		}
+	}
+}
+
+func TestBatchProcessInclusionProofs_MemoizationCrossChecksAncestors(t *testing.T) {
+	leaves := [4]common.Hash{
+		Keccak256Hasher([]byte("leaf0")),
+		Keccak256Hasher([]byte("leaf1")),
+		Keccak256Hasher([]byte("leaf2")),
+		Keccak256Hasher([]byte("leaf3")),
+	}
+	_, certs := buildFourLeafTree(leaves, Keccak256Hasher)
+
+	// leaves 0 and 1 share the same level-1 ancestor (n01) and the same root; processing
+	// both through the same memo map should accept cert 1 because its own proof
+	// recomputes the exact ancestor hash cert 0 already cached.
+	memo := make(map[memoKey]common.Hash)
+	root0, err := processWithMemo(certs[0].Proof, certs[0].Leaf, certs[0].Index, Keccak256Hasher, memo)
+	if err != nil {
+		t.Fatalf("cert 0: processWithMemo failed: %v", err)
+	}
+
+	sizeAfterFirst := len(memo)
+	root1, err := processWithMemo(certs[1].Proof, certs[1].Leaf, certs[1].Index, Keccak256Hasher, memo)
+	if err != nil {
+		t.Fatalf("cert 1: processWithMemo failed: %v", err)
+	}
+
+	if root0 != root1 {
+		t.Fatalf("expected sibling leaves to compute the same root, got %s and %s", root0, root1)
+	}
+	// cert 1 only introduces its own leaf entry (depth 0); the level-1 ancestor and the
+	// root it recomputes match what cert 0 already cached, so the memo should grow by
+	// exactly one entry.
+	if grew := len(memo) - sizeAfterFirst; grew != 1 {
+		t.Fatalf("expected memo to grow by 1 entry (new leaf only) when processing a sibling leaf, grew by %d", grew)
+	}
+}
+
+func TestBatchProcessInclusionProofs_RejectsForgedCertRidingCachedAncestor(t *testing.T) {
+	leaves := [4]common.Hash{
+		Keccak256Hasher([]byte("leaf0")),
+		Keccak256Hasher([]byte("leaf1")),
+		Keccak256Hasher([]byte("leaf2")),
+		Keccak256Hasher([]byte("leaf3")),
+	}
+	_, certs := buildFourLeafTree(leaves, Keccak256Hasher)
+
+	// a forged cert: same index as a legitimate cert already in the batch, but a
+	// fabricated leaf and an arbitrary (still 32-byte, so well-formed) sibling hash. If
+	// memoization trusted the cached ancestor instead of cross-checking it, this forged
+	// cert would come back validated against the real root.
+	forged := LeafProof{
+		Leaf:  Keccak256Hasher([]byte("not a real leaf")),
+		Index: certs[0].Index,
+		Proof: append(append([]byte{}, Keccak256Hasher([]byte("garbage sibling")).Bytes()...), certs[0].Proof[32:]...),
+	}
+
+	_, err := BatchProcessInclusionProofs([]LeafProof{certs[0], forged}, Keccak256Hasher)
+	if err == nil {
+		t.Fatal("expected a forged cert sharing a cached ancestor to be rejected, not silently validated")
+	}
+}
+
+func TestBatchProcessInclusionProofs_MalformedProof(t *testing.T) {
+	certs := []LeafProof{
+		{Leaf: Keccak256Hasher([]byte("leaf0")), Index: 0, Proof: make([]byte, 17)},
+	}
+	if _, err := BatchProcessInclusionProofs(certs, Keccak256Hasher); err == nil {
+		t.Fatal("expected an error for a proof whose length is not a multiple of 32 bytes")
+	}
+}